@@ -4,8 +4,10 @@ import (
 	"html/template"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/akos011221/sigma/core"
+	"github.com/akos011221/sigma/health"
 )
 
 // Component represents a reusable UI element with state
@@ -31,6 +33,18 @@ type Component struct {
 	// onUpdate is an optional callback for handling
 	// event (e.g., button clicks).
 	onUpdate func(*Component, *core.Context)
+
+	// broadcaster is set by core.Sigma.RegisterComponent via
+	// SetBroadcaster. When non-nil, SetState and Update publish a
+	// change notification to it so live transports (SSE, WebSocket)
+	// know to re-render instead of polling.
+	broadcaster *core.Broadcaster
+
+	// health tracks this component's liveness: its current
+	// state/message, when it was last touched, and consecutive
+	// Render failures. Exposed to core.Sigma's health endpoints via
+	// Health(), which implements core.HealthChecker.
+	health *health.Tracker
 }
 
 // NewComponent creates a new component instance.
@@ -53,6 +67,7 @@ func NewComponent(name, tmpl string, initialState map[string]interface{}, onUpda
 		state:    initialState,
 		template: tmpl,
 		onUpdate: onUpdate,
+		health:   health.NewTracker(3), // degrade after 3 consecutive Render failures
 	}
 }
 
@@ -80,19 +95,24 @@ func (c *Component) Render() (string, error) {
 	// like {{.Count}} with actual values.
 	err := tmpl.Execute(&buf, c.state)
 	if err != nil {
+		c.health.RecordFailure(err)
 		return "", err // e.g., invalid state
 	}
+	c.health.RecordSuccess()
 
 	// Return the final HTML string.
 	return buf.String(), nil
 }
 
 // Update applies an event to the component (e.g., incrementing
-// a counter). It calls the onUpdate callback if it exists.
+// a counter). It calls the onUpdate callback if it exists, then
+// publishes a change notification so subscribed clients re-render.
 func (c *Component) Update(ctx *core.Context) {
+	c.health.Touch()
 	if c.onUpdate != nil {
 		c.onUpdate(c, ctx)
 	}
+	c.publish()
 }
 
 // State returns a read-only view of the component's state.
@@ -103,11 +123,54 @@ func (c *Component) State() map[string]interface{} {
 	return c.state
 }
 
-// SetState updates a specific key in the state map.
+// SetState updates a specific key in the state map, then
+// publishes a change notification so subscribed clients re-render.
 // With this method it is possible to modify state
 // from outside the package.
 func (c *Component) SetState(key string, value interface{}) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.state[key] = value
+	c.mu.Unlock()
+	c.health.Touch()
+	c.publish()
+}
+
+// SetHealth manually overrides this component's reported health,
+// e.g. a background worker it depends on just failed in a way
+// Render/Update wouldn't otherwise catch. state should be one of
+// health.Healthy, health.Degraded, health.Failed, or
+// health.Starting. The override sticks across subsequent
+// Render/Update calls - an unrelated successful render doesn't
+// clear it. Calling SetHealth again replaces it with a new
+// override; automatic tracking never resumes.
+func (c *Component) SetHealth(state, msg string) {
+	c.health.Set(health.State(state), msg)
+}
+
+// Health implements core.HealthChecker, reporting this component's
+// current state, message, and the last time SetState/Update/Render
+// touched it.
+func (c *Component) Health() (state, message string, lastUpdate time.Time) {
+	return c.health.Snapshot()
+}
+
+// SetBroadcaster implements core.Notifier. core.Sigma calls it from
+// RegisterComponent so this component can publish its own change
+// notifications.
+func (c *Component) SetBroadcaster(b *core.Broadcaster) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.broadcaster = b
+}
+
+// publish notifies the component's broadcaster, if any, that its
+// state changed. It's a no-op until the component has been
+// registered with a Sigma instance.
+func (c *Component) publish() {
+	c.mu.Lock()
+	b := c.broadcaster
+	c.mu.Unlock()
+	if b != nil {
+		b.Publish()
+	}
 }
@@ -0,0 +1,22 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/akos011221/sigma/health"
+)
+
+func TestSetHealthSurvivesRender(t *testing.T) {
+	c := NewComponent("widget", `{{.Message}}`, map[string]interface{}{"Message": "hi"}, nil)
+
+	c.SetHealth(string(health.Failed), "dependency down")
+
+	if _, err := c.Render(); err != nil {
+		t.Fatalf("unexpected render error: %v", err)
+	}
+
+	state, msg, _ := c.Health()
+	if state != string(health.Failed) || msg != "dependency down" {
+		t.Fatalf("expected a manually-set Failed state to survive a successful render, got state=%q msg=%q", state, msg)
+	}
+}
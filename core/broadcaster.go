@@ -0,0 +1,82 @@
+package core
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is how many pending notifications a single
+// subscriber channel can hold before it's considered a slow
+// consumer. This keeps one stalled client from blocking the
+// publisher or growing memory without bound.
+const subscriberBuffer = 8
+
+// Broadcaster fans out change notifications to any number of
+// subscribers (one per connected SSE/WebSocket client). It's
+// intentionally dumb: the payload is just "something changed",
+// not the change itself, so subscribers always re-render from
+// the component's current state rather than trying to replay
+// a diff.
+type Broadcaster struct {
+	// mu protects subscribers, since Subscribe/Unsubscribe/Publish
+	// can all be called from different goroutines (one per
+	// connected client, plus whatever goroutine mutates state).
+	mu sync.Mutex
+
+	// subscribers maps each subscriber's channel to an empty
+	// struct, used as a set so Unsubscribe is an O(1) delete.
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewBroadcaster creates an empty Broadcaster ready to use.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its channel.
+// The caller (typically SSEHandler or WebSocketHandler) should
+// select on it and call Unsubscribe when done, usually via defer.
+func (b *Broadcaster) Subscribe() chan struct{} {
+	ch := make(chan struct{}, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[ch] = struct{}{}
+
+	return ch
+}
+
+// Unsubscribe removes a subscriber and closes its channel. It's
+// safe to call more than once for the same channel.
+func (b *Broadcaster) Unsubscribe(ch chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; !ok {
+		return
+	}
+	delete(b.subscribers, ch)
+	close(ch)
+}
+
+// Publish notifies every current subscriber that something
+// changed. Slow consumers whose buffer is already full are
+// skipped rather than blocked on - a dropped notification loses
+// nothing, since that client still has notifications queued and
+// re-renders from the component's current state when it drains
+// them.
+func (b *Broadcaster) Publish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// Slow-consumer drop: the subscriber hasn't drained
+			// its buffer yet, so don't pile up more notifications
+			// behind it.
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import "testing"
+
+func TestBroadcasterPublishDeliversToSubscribers(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe()
+
+	b.Publish()
+
+	select {
+	case <-ch:
+	default:
+		t.Fatalf("expected a notification on the subscriber channel after Publish")
+	}
+}
+
+func TestBroadcasterUnsubscribeClosesChannel(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe()
+
+	b.Unsubscribe(ch)
+
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected the channel to be closed after Unsubscribe")
+	}
+}
+
+func TestBroadcasterSlowConsumerDropsInsteadOfBlocking(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe()
+
+	// Fill the subscriber's buffer, then publish one more than it can
+	// hold. None of these calls should block.
+	for i := 0; i < subscriberBuffer+1; i++ {
+		b.Publish()
+	}
+
+	if len(ch) != subscriberBuffer {
+		t.Fatalf("expected the channel to be full at %d, got %d", subscriberBuffer, len(ch))
+	}
+}
+
+func TestBroadcasterUnsubscribeIsIdempotent(t *testing.T) {
+	b := NewBroadcaster()
+	ch := b.Subscribe()
+
+	b.Unsubscribe(ch)
+	b.Unsubscribe(ch) // must not panic (double close)
+}
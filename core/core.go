@@ -2,28 +2,50 @@ package core
 
 import (
 	"net/http"
+	"strings"
 	"sync"
 )
 
 // Sigma is the central struct of the framework, managing routes and components.
 // It acts as the "core" that ties everything together.
 type Sigma struct {
-	// routes maps HTTP methods (e.g., "GET", "POST") to a map of paths
-	// (e.g., "/home"). This nested map structure allows method-specific
-	// routing: routes["GET"]["/home"]
-	routes map[string]map[string]HandlerFunc
+	// router is a radix-style route tree supporting static
+	// segments, ":name" params, and a trailing "*rest" catch-all.
+	// It replaces the old flat method->path map, which could only
+	// do exact matches.
+	router *router
 
 	// components maps components names (e.g., "counter") to their
 	// implementations. This registry lets us look up components
 	// by name when rendering or updating.
 	components map[string]ComponentInterface
 
+	// broadcasters maps a component name to the single Broadcaster
+	// that fans out its change notifications. Keeping one per
+	// component (rather than one per SSE route) means several
+	// routes serving the same component share a publisher instead
+	// of each re-rendering independently.
+	broadcasters map[string]*Broadcaster
+
+	// middleware holds the global chain, run for every route
+	// before its own handler. Handle prepends it to each route's
+	// chain at registration time.
+	middleware []Middleware
+
 	// mu is to prevent race conditions. Since Go's HTTP server runs
 	// in a seperate goroutine, multiple goroutines could modify
 	// routes or components concurrently without this.
 	mu sync.Mutex
 }
 
+// Middleware has the same shape as HandlerFunc: it receives the
+// Context and may call ctx.Next() to run the rest of the chain
+// before doing its own post-processing, or ctx.Abort() to stop the
+// chain, gin-style. Keeping it the same type as
+// HandlerFunc means a route's final handler just becomes the last
+// link in the chain.
+type Middleware = HandlerFunc
+
 // HandlerFunc takes a *Context and handles an HTTP request. It is a custom
 // defined HandlerFunc that works with Sigma's Context struct, giving more
 // control.
@@ -47,6 +69,67 @@ type Context struct {
 	// It's a simple key-value map, populated by the router when
 	// matching dynamic paths.
 	Params map[string]string
+
+	// handlers is the full middleware+handler chain for this route,
+	// in execution order. index tracks which link is currently
+	// running; Next advances it. Both are set up by Sigma before
+	// the chain starts and shouldn't be touched directly.
+	handlers []HandlerFunc
+	index    int
+
+	// store holds arbitrary per-request values set by middleware
+	// (e.g. a request ID) for downstream middleware/handlers to
+	// read via Set/Get.
+	store   map[string]interface{}
+	storeMu sync.Mutex
+}
+
+// Next runs the remaining handlers in the chain. Middleware calls it
+// to run the rest of the chain before its own code continues; if it
+// doesn't call Next, the remaining handlers still run once it
+// returns. Only Abort stops the chain, e.g. an auth middleware
+// rejecting the request.
+func (c *Context) Next() {
+	c.index++
+	for c.index < len(c.handlers) {
+		c.handlers[c.index](c)
+		c.index++
+	}
+}
+
+// Abort stops the chain after the current handler returns, by
+// skipping straight past the remaining links. It's the only way for
+// a handler/middleware to stop the chain; simply not calling Next
+// doesn't.
+func (c *Context) Abort() {
+	c.index = len(c.handlers)
+}
+
+// IsAborted reports whether the chain has been stopped, either by
+// Abort or by running off the end naturally.
+func (c *Context) IsAborted() bool {
+	return c.index >= len(c.handlers)
+}
+
+// Set stores a value under key for the lifetime of this request,
+// e.g. a request ID set by middleware.RequestID. Safe for
+// concurrent use, though in practice a single request is handled
+// on one goroutine.
+func (c *Context) Set(key string, value interface{}) {
+	c.storeMu.Lock()
+	defer c.storeMu.Unlock()
+	if c.store == nil {
+		c.store = make(map[string]interface{})
+	}
+	c.store[key] = value
+}
+
+// Get retrieves a value previously stored with Set.
+func (c *Context) Get(key string) (interface{}, bool) {
+	c.storeMu.Lock()
+	defer c.storeMu.Unlock()
+	v, ok := c.store[key]
+	return v, ok
 }
 
 // ComponentInterface defines the contract all components must follow.
@@ -63,48 +146,156 @@ type ComponentInterface interface {
 	State() map[string]interface{}
 }
 
+// Notifier is implemented by components that want to publish a
+// change notification whenever their state changes, so live
+// transports (SSE, WebSocket) know to re-render instead of
+// polling. It's kept separate from ComponentInterface so
+// components that never need live updates aren't forced to
+// implement it.
+type Notifier interface {
+	// SetBroadcaster gives the component the Broadcaster it should
+	// publish to. Sigma calls this from RegisterComponent.
+	SetBroadcaster(*Broadcaster)
+}
+
 // New creates a new Sigma instance.
 func New() *Sigma {
 	return &Sigma{
-		// Intialize routes as a nested map. Method->Path->Handler
-		routes: make(map[string]map[string]HandlerFunc),
+		// Initialize the route tree, empty until Handle is called.
+		router: newRouter(),
 
 		// Initialize components to store registered components.
 		components: make(map[string]ComponentInterface),
+
+		// Initialize broadcasters to store each component's
+		// publisher, created lazily in RegisterComponent.
+		broadcasters: make(map[string]*Broadcaster),
 	}
 }
 
 // Handle registers a route for a specific HTTP method and path.
-// It updates the routes map in a thread-safe way.
-func (s *Sigma) Handle(method, path string, handler HandlerFunc) {
-	// Lock the mutex to prevent concurrent writes to routes.
-	// Required, so multiple goroutines don't corrupt the map.
+// path may contain ":name" params (e.g. "/users/:id") and a
+// trailing "*rest" catch-all (e.g. "/files/*path"). The final chain
+// run for this route is Sigma's global middleware (from Use),
+// followed by any route-specific mw given here, followed by
+// handler. It updates the route tree in a thread-safe way.
+func (s *Sigma) Handle(method, path string, handler HandlerFunc, mw ...Middleware) {
+	s.mu.Lock()
+	chain := make([]HandlerFunc, 0, len(s.middleware)+len(mw)+1)
+	chain = append(chain, s.middleware...)
+	chain = append(chain, mw...)
+	chain = append(chain, handler)
+	s.router.add(method, path, composeChain(chain))
+	s.mu.Unlock()
+}
+
+// composeChain wraps a middleware+handler chain into a single
+// HandlerFunc: it seeds the Context's chain state and kicks it off
+// with Next(). This is what actually gets stored in the route
+// tree, so ServeHTTP doesn't need to know about chains at all.
+func composeChain(chain []HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		ctx.handlers = chain
+		ctx.index = -1
+		ctx.Next()
+	}
+}
+
+// Use registers middleware that runs for every route on this
+// Sigma instance, in the order given, before each route's own
+// middleware/handler. It must be called before the routes it
+// should apply to are registered with Handle.
+func (s *Sigma) Use(mw ...Middleware) {
 	s.mu.Lock()
-	// Unlock when the function exists.
 	defer s.mu.Unlock()
+	s.middleware = append(s.middleware, mw...)
+}
 
-	// If no map exists for this method (e.g., "GET), create one.
-	// This lazy initialization avoids pre-allocating for unused
-	// methods.
-	if s.routes[method] == nil {
-		s.routes[method] = make(map[string]HandlerFunc)
+// Group creates a RouterGroup rooted at prefix, with mw applied to
+// every route registered through it (in addition to Sigma's global
+// middleware). Groups are how a set of routes shares a path prefix
+// and a set of middleware, e.g. an "/api" group with an auth check.
+func (s *Sigma) Group(prefix string, mw ...Middleware) *RouterGroup {
+	return &RouterGroup{
+		sigma:      s,
+		prefix:     prefix,
+		middleware: append([]Middleware{}, mw...),
 	}
+}
+
+// GET registers a handler for GET requests to path.
+func (s *Sigma) GET(path string, handler HandlerFunc, mw ...Middleware) {
+	s.Handle(http.MethodGet, path, handler, mw...)
+}
+
+// POST registers a handler for POST requests to path.
+func (s *Sigma) POST(path string, handler HandlerFunc, mw ...Middleware) {
+	s.Handle(http.MethodPost, path, handler, mw...)
+}
+
+// PUT registers a handler for PUT requests to path.
+func (s *Sigma) PUT(path string, handler HandlerFunc, mw ...Middleware) {
+	s.Handle(http.MethodPut, path, handler, mw...)
+}
+
+// DELETE registers a handler for DELETE requests to path.
+func (s *Sigma) DELETE(path string, handler HandlerFunc, mw ...Middleware) {
+	s.Handle(http.MethodDelete, path, handler, mw...)
+}
+
+// PATCH registers a handler for PATCH requests to path.
+func (s *Sigma) PATCH(path string, handler HandlerFunc, mw ...Middleware) {
+	s.Handle(http.MethodPatch, path, handler, mw...)
+}
+
+// anyMethods lists the HTTP methods Any registers a handler for.
+var anyMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut,
+	http.MethodDelete, http.MethodPatch, http.MethodHead,
+	http.MethodOptions,
+}
 
-	// Assign the handler to the path for this method.
-	// The handler is just a function pointer stored
-	// in the map.
-	s.routes[method][path] = handler
+// Any registers handler for path under every method in
+// anyMethods, for routes that don't care which verb was used.
+func (s *Sigma) Any(path string, handler HandlerFunc, mw ...Middleware) {
+	for _, method := range anyMethods {
+		s.Handle(method, path, handler, mw...)
+	}
 }
 
-// RegisterComponent adds a component to the registry.
-func (s *Sigma) RegisterComponent(c ComponentInterface) {
+// RegisterComponent adds a component to the registry and gives it
+// a Broadcaster to publish change notifications to. It returns the
+// Broadcaster so callers can hand it to realtime.SSEHandler or
+// realtime.WebSocketHandler; every route built on the same
+// component shares this one instance.
+func (s *Sigma) RegisterComponent(c ComponentInterface) *Broadcaster {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// Use the component's "name" from its state as the key.
 	// This assumes every component has a "name" in its
 	// state map.
-	s.components[c.State()["name"].(string)] = c
+	name := c.State()["name"].(string)
+	s.components[name] = c
+
+	b := NewBroadcaster()
+	s.broadcasters[name] = b
+
+	// If the component can receive a Broadcaster, wire it up so its
+	// own SetState/Update publish a notification automatically.
+	if n, ok := c.(Notifier); ok {
+		n.SetBroadcaster(b)
+	}
+
+	return b
+}
+
+// Broadcaster returns the Broadcaster registered for the named
+// component, or nil if no such component was registered.
+func (s *Sigma) Broadcaster(name string) *Broadcaster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.broadcasters[name]
 }
 
 // ServeHTTP makes Sigma implement the http.Handler interface.
@@ -112,32 +303,31 @@ func (s *Sigma) RegisterComponent(c ComponentInterface) {
 // request. It's the entry point where requests are routed to 
 // handlers.
 func (s *Sigma) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Look up routes for the request's method (e.g., "GET").
-	// methodRoutes is a map of paths to handlers, or nil if
-	// the method isn't supported
-	methodRoutes, ok := s.routes[r.Method]
-	if !ok {
-		// If no routes exist for this method, return a 405 error.
-		// http.Error writes the status code and message to w.
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Look up the handler for the exact path (e.g., "/home").
-	// handler is a HandlerFunc (Sigma's custom function type),
-	// or nil if not found.
-	handler, ok := methodRoutes[r.URL.Path]
+	// Walk the route tree for this method and path. params holds
+	// any ":name"/"*rest" segments captured along the way, and
+	// methods lists every method registered at this path - used to
+	// tell a 404 (path doesn't exist) apart from a 405 (path
+	// exists, wrong verb).
+	handler, params, methods, ok := s.router.match(r.Method, r.URL.Path)
 	if !ok {
-		// If no handler matches the path, return a 404 error.
+		if len(methods) > 0 {
+			// The path exists for other methods, so this is a 405,
+			// not a 404. Advertise the allowed methods so a
+			// well-behaved client can retry correctly.
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// No route at all matches this path.
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
 	}
 
 	// Create a new Context for this request.
 	ctx := &Context{
-		Req:	r,	// Pass the original request
-		Resp:	w,	// Pass the original response writer
-		Params: make(map[string]string), // Initialize an empty params map
+		Req:    r,      // Pass the original request
+		Resp:   w,      // Pass the original response writer
+		Params: params, // Params captured by the route tree
 	}
 
 	// Call the handler with the context.
@@ -0,0 +1,75 @@
+package core
+
+import "net/http"
+
+// RouterGroup is a set of routes sharing a path prefix and a
+// middleware chain, e.g. an "/api" group with an auth check
+// applied to everything under it. Groups can be nested: a child
+// group inherits its parent's prefix and middleware and adds its
+// own on top.
+type RouterGroup struct {
+	sigma      *Sigma
+	prefix     string
+	middleware []Middleware
+}
+
+// Use appends middleware to this group's chain, applied to every
+// route registered through it (and through any subgroup created
+// after this call) from this point on. Subgroups created earlier
+// keep the copy of the middleware they took at creation. Returns the
+// group so calls can be chained.
+func (g *RouterGroup) Use(mw ...Middleware) *RouterGroup {
+	g.middleware = append(g.middleware, mw...)
+	return g
+}
+
+// Group creates a subgroup rooted at g.prefix+prefix, inheriting
+// g's middleware and adding mw on top of it.
+func (g *RouterGroup) Group(prefix string, mw ...Middleware) *RouterGroup {
+	inherited := append([]Middleware{}, g.middleware...)
+	return &RouterGroup{
+		sigma:      g.sigma,
+		prefix:     g.prefix + prefix,
+		middleware: append(inherited, mw...),
+	}
+}
+
+// Handle registers handler for method and g.prefix+path, running
+// g's middleware (and Sigma's global middleware) before it.
+func (g *RouterGroup) Handle(method, path string, handler HandlerFunc, mw ...Middleware) {
+	chain := append(append([]Middleware{}, g.middleware...), mw...)
+	g.sigma.Handle(method, g.prefix+path, handler, chain...)
+}
+
+// GET registers a handler for GET requests to g.prefix+path.
+func (g *RouterGroup) GET(path string, handler HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodGet, path, handler, mw...)
+}
+
+// POST registers a handler for POST requests to g.prefix+path.
+func (g *RouterGroup) POST(path string, handler HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodPost, path, handler, mw...)
+}
+
+// PUT registers a handler for PUT requests to g.prefix+path.
+func (g *RouterGroup) PUT(path string, handler HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodPut, path, handler, mw...)
+}
+
+// DELETE registers a handler for DELETE requests to g.prefix+path.
+func (g *RouterGroup) DELETE(path string, handler HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodDelete, path, handler, mw...)
+}
+
+// PATCH registers a handler for PATCH requests to g.prefix+path.
+func (g *RouterGroup) PATCH(path string, handler HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodPatch, path, handler, mw...)
+}
+
+// Any registers handler for g.prefix+path under every method in
+// anyMethods.
+func (g *RouterGroup) Any(path string, handler HandlerFunc, mw ...Middleware) {
+	for _, method := range anyMethods {
+		g.Handle(method, path, handler, mw...)
+	}
+}
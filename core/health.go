@@ -0,0 +1,91 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthChecker is implemented by components that can report their
+// own health, so Sigma's Liveness/Readiness/Status endpoints can
+// aggregate it. It's kept separate from ComponentInterface so
+// components that don't track health aren't forced to implement
+// it. State is expected to be one of "healthy", "degraded",
+// "failed", or "starting" (see the health package), but Sigma
+// doesn't enforce that - it just passes the string through.
+type HealthChecker interface {
+	Health() (state, message string, lastUpdate time.Time)
+}
+
+// componentStatus is the JSON shape the Status handler serves for
+// one component.
+type componentStatus struct {
+	Name       string    `json:"name"`
+	State      string    `json:"state"`
+	Message    string    `json:"message,omitempty"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// healthSnapshot collects a componentStatus for every registered
+// component that implements HealthChecker. Components that don't
+// implement it simply have no opinion on their health and are
+// left out.
+func (s *Sigma) healthSnapshot() []componentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]componentStatus, 0, len(s.components))
+	for name, c := range s.components {
+		hc, ok := c.(HealthChecker)
+		if !ok {
+			continue
+		}
+		state, message, lastUpdate := hc.Health()
+		statuses = append(statuses, componentStatus{
+			Name:       name,
+			State:      state,
+			Message:    message,
+			LastUpdate: lastUpdate,
+		})
+	}
+	return statuses
+}
+
+// Liveness returns a handler that reports 200 as long as the
+// process can handle a request at all - it doesn't look at
+// component health. Point an orchestrator's liveness probe at it;
+// a failure here means "restart the process", not "stop sending
+// traffic".
+func (s *Sigma) Liveness() HandlerFunc {
+	return func(ctx *Context) {
+		ctx.Resp.WriteHeader(http.StatusOK)
+	}
+}
+
+// Readiness returns a handler that reports 200 only if every
+// HealthChecker component is currently Healthy; any component
+// that's Starting, Degraded, or Failed yields 503. Point an
+// orchestrator's readiness probe at it so it stops routing traffic
+// here while a component is unwell, without killing the process.
+func (s *Sigma) Readiness() HandlerFunc {
+	return func(ctx *Context) {
+		for _, st := range s.healthSnapshot() {
+			if st.State != "healthy" {
+				ctx.Resp.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		ctx.Resp.WriteHeader(http.StatusOK)
+	}
+}
+
+// Status returns a handler serving a JSON array with every
+// HealthChecker component's name, state, message, and last-update
+// time - useful for a dashboard, or for debugging why Readiness is
+// failing.
+func (s *Sigma) Status() HandlerFunc {
+	return func(ctx *Context) {
+		ctx.Resp.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(ctx.Resp).Encode(s.healthSnapshot())
+	}
+}
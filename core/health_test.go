@@ -0,0 +1,113 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// healthStubComponent is a minimal ComponentInterface + HealthChecker
+// for exercising Sigma's health endpoints without pulling in the
+// components package.
+type healthStubComponent struct {
+	name       string
+	state      string
+	message    string
+	lastUpdate time.Time
+}
+
+func (h *healthStubComponent) Render() (string, error) { return "", nil }
+func (h *healthStubComponent) Update(*Context)         {}
+func (h *healthStubComponent) State() map[string]interface{} {
+	return map[string]interface{}{"name": h.name}
+}
+func (h *healthStubComponent) Health() (state, message string, lastUpdate time.Time) {
+	return h.state, h.message, h.lastUpdate
+}
+
+// unhealthyComponent is a ComponentInterface that doesn't implement
+// HealthChecker, to verify Status/Readiness skip it rather than
+// panicking on a failed type assertion.
+type unhealthyComponent struct{}
+
+func (unhealthyComponent) Render() (string, error) { return "", nil }
+func (unhealthyComponent) Update(*Context)         {}
+func (unhealthyComponent) State() map[string]interface{} {
+	return map[string]interface{}{"name": "no-health"}
+}
+
+func serveHandler(h HandlerFunc) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h(&Context{Req: httptest.NewRequest(http.MethodGet, "/", nil), Resp: rec})
+	return rec
+}
+
+func TestLivenessIgnoresComponentHealth(t *testing.T) {
+	s := New()
+	s.RegisterComponent(&healthStubComponent{name: "a", state: "failed"})
+
+	rec := serveHandler(s.Liveness())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected Liveness to report 200 regardless of component health, got %d", rec.Code)
+	}
+}
+
+func TestReadinessOKWhenAllComponentsHealthy(t *testing.T) {
+	s := New()
+	s.RegisterComponent(&healthStubComponent{name: "a", state: "healthy"})
+	s.RegisterComponent(&healthStubComponent{name: "b", state: "healthy"})
+
+	rec := serveHandler(s.Readiness())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 when every component is healthy, got %d", rec.Code)
+	}
+}
+
+func TestReadinessUnavailableWhenAnyComponentIsUnwell(t *testing.T) {
+	for _, state := range []string{"degraded", "failed", "starting"} {
+		s := New()
+		s.RegisterComponent(&healthStubComponent{name: "a", state: "healthy"})
+		s.RegisterComponent(&healthStubComponent{name: "b", state: state})
+
+		rec := serveHandler(s.Readiness())
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503 with a %q component, got %d", state, rec.Code)
+		}
+	}
+}
+
+func TestReadinessIgnoresComponentsWithoutHealthChecker(t *testing.T) {
+	s := New()
+	s.RegisterComponent(unhealthyComponent{})
+
+	rec := serveHandler(s.Readiness())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 when no registered component reports health, got %d", rec.Code)
+	}
+}
+
+func TestStatusSerializesComponentsAndSkipsNonHealthCheckers(t *testing.T) {
+	s := New()
+	lastUpdate := time.Now().Truncate(time.Second)
+	s.RegisterComponent(&healthStubComponent{name: "a", state: "degraded", message: "slow", lastUpdate: lastUpdate})
+	s.RegisterComponent(unhealthyComponent{})
+
+	rec := serveHandler(s.Status())
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+
+	var got []componentStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected JSON decode error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected only the HealthChecker component in the status, got %v", got)
+	}
+	if got[0].Name != "a" || got[0].State != "degraded" || got[0].Message != "slow" || !got[0].LastUpdate.Equal(lastUpdate) {
+		t.Fatalf("unexpected status entry: %+v", got[0])
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddlewareRunsGlobalThenGroupThenRouteInOrder(t *testing.T) {
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(ctx *Context) {
+			order = append(order, name)
+			ctx.Next()
+		}
+	}
+
+	s := New()
+	s.Use(mw("global"))
+
+	g := s.Group("/api", mw("group"))
+	g.GET("/ping", func(ctx *Context) {
+		order = append(order, "handler")
+	}, mw("route"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	want := []string{"global", "group", "route", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestMiddlewareAbortStopsTheChain(t *testing.T) {
+	var ran []string
+
+	s := New()
+	s.GET("/guarded", func(ctx *Context) {
+		ran = append(ran, "handler")
+	}, func(ctx *Context) {
+		ran = append(ran, "gate")
+		ctx.Resp.WriteHeader(http.StatusForbidden)
+		ctx.Abort()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/guarded", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if len(ran) != 1 || ran[0] != "gate" {
+		t.Fatalf("expected only the gating middleware to run, got %v", ran)
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestContextSetGet(t *testing.T) {
+	ctx := &Context{}
+
+	if _, ok := ctx.Get("missing"); ok {
+		t.Fatalf("expected Get on an empty store to report not found")
+	}
+
+	ctx.Set("key", "value")
+	v, ok := ctx.Get("key")
+	if !ok || v != "value" {
+		t.Fatalf("expected Get to return the value set via Set, got %v, %v", v, ok)
+	}
+}
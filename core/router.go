@@ -0,0 +1,256 @@
+package core
+
+import "strings"
+
+// routerNode is one segment of the route tree. A path like
+// "/users/:id/posts" is stored as a chain of nodes, one per
+// slash-separated segment, so "/users/1/posts" and
+// "/users/2/posts" share every node up to ":id".
+type routerNode struct {
+	// children maps a literal segment (e.g. "users") to the node
+	// for that segment. Checked before paramChild/catchAllChild,
+	// so a static route always wins over a param route at the
+	// same position.
+	children map[string]*routerNode
+
+	// paramChild is the node for a ":name" segment at this
+	// position, if any. paramName is the part after the colon,
+	// used as the key in ctx.Params.
+	paramChild *routerNode
+	paramName  string
+
+	// catchAllChild is the node for a "*rest" segment, if any. It
+	// always terminates the route, since it swallows everything
+	// left in the path.
+	catchAllChild *routerNode
+	catchAllName  string
+
+	// handlers maps an HTTP method to the handler registered for
+	// this exact node, i.e. this exact path.
+	handlers map[string]HandlerFunc
+}
+
+func newRouterNode() *routerNode {
+	return &routerNode{
+		children: make(map[string]*routerNode),
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// router is a radix-style route tree keyed on path segments, with
+// support for ":name" params and a trailing "*rest" catch-all. It
+// replaces the old map[method]map[path]HandlerFunc table, which
+// couldn't express dynamic segments at all.
+type router struct {
+	root *routerNode
+}
+
+func newRouter() *router {
+	return &router{root: newRouterNode()}
+}
+
+// splitPath breaks a path into its non-empty segments, so "/" is
+// nil and "/users/1/" is []string{"users", "1"}.
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// add registers handler for method and path, walking/creating
+// nodes for each segment. A ":name" segment becomes a param node;
+// a "*rest" segment becomes a catch-all and must be the last
+// segment in path.
+func (rt *router) add(method, path string, handler HandlerFunc) {
+	segments := splitPath(path)
+	node := rt.root
+
+	for _, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			if node.paramChild == nil {
+				node.paramChild = newRouterNode()
+				node.paramName = seg[1:]
+			}
+			node = node.paramChild
+
+		case strings.HasPrefix(seg, "*"):
+			if node.catchAllChild == nil {
+				node.catchAllChild = newRouterNode()
+				node.catchAllName = seg[1:]
+			}
+			// A catch-all swallows everything after it. Any further
+			// segments in path are still added below this node, but
+			// matchNode never walks past a catch-all, so a route
+			// registered that way is never reachable.
+			node = node.catchAllChild
+
+		default:
+			child, ok := node.children[seg]
+			if !ok {
+				child = newRouterNode()
+				node.children[seg] = child
+			}
+			node = child
+		}
+	}
+
+	node.handlers[method] = handler
+}
+
+// match walks the tree for path and returns the handler for
+// method, the params collected along the way, and the set of
+// methods registered at that path (used to build the Allow header
+// and to tell a 404 apart from a 405).
+func (rt *router) match(method, path string) (handler HandlerFunc, params map[string]string, methods []string, found bool) {
+	segments := splitPath(path)
+	params = make(map[string]string)
+
+	if node := matchNode(rt.root, segments, params, method); node != nil {
+		return node.handlers[method], params, methodsOf(node), true
+	}
+
+	// No node along any branch serves method for this path, but the
+	// path may still exist for other methods (a 405, not a 404) -
+	// e.g. a literal node that only serves other methods was
+	// rejected above and backtracked past, and no param or
+	// catch-all branch serves method either. Collect
+	// the union of methods from every node at this path shape -
+	// literal, param, and catch-all alike - not just whichever one a
+	// shape-only walk would hit first, since a literal and a param
+	// route at the same position can have completely disjoint method
+	// sets (e.g. "POST /users/export" and "PUT /users/:id": a
+	// non-POST, non-PUT request to "/users/export" must report
+	// Allow: POST, PUT, not just one of them).
+	set := make(map[string]struct{})
+	collectMethods(rt.root, segments, set)
+	if len(set) == 0 {
+		return nil, nil, nil, false
+	}
+
+	methods = make([]string, 0, len(set))
+	for m := range set {
+		methods = append(methods, m)
+	}
+	return nil, nil, methods, false
+}
+
+// methodsOf lists the HTTP methods registered on node's handlers,
+// for the Allow header and for telling a 404 apart from a 405.
+func methodsOf(node *routerNode) []string {
+	methods := make([]string, 0, len(node.handlers))
+	for m := range node.handlers {
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// collectMethods gathers every HTTP method registered at path shape
+// segments from node, across all branches - unlike matchNode, it
+// doesn't stop at the first match, since a literal and a param/
+// catch-all route can both exist at the same position with
+// different methods and the Allow header needs to report all of
+// them.
+func collectMethods(node *routerNode, segments []string, set map[string]struct{}) {
+	if len(segments) == 0 {
+		for m := range node.handlers {
+			set[m] = struct{}{}
+		}
+		return
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	if child, ok := node.children[seg]; ok {
+		collectMethods(child, rest, set)
+	}
+
+	if node.paramChild != nil {
+		collectMethods(node.paramChild, rest, set)
+	}
+
+	if node.catchAllChild != nil {
+		for m := range node.catchAllChild.handlers {
+			set[m] = struct{}{}
+		}
+	}
+}
+
+// matchNode walks segments from node, trying the literal child
+// first, then the param child, then the catch-all child at each
+// position - and backtracks to the next alternative if a branch it
+// committed to turns out to be a dead end further down. Without
+// this, a literal subtree that doesn't contain the requested path
+// would wrongly shadow a param route registered at the same
+// position (e.g. "/a/b/c" and "/a/:id/d" both registered: "/a/b/d"
+// must fall through "b"'s literal subtree and match via :id).
+// params is filled in as the match proceeds and trimmed back out
+// on backtrack, so a failed branch doesn't leak captures into the
+// eventual successful one.
+//
+// A node with no segments left is only a match if it actually has
+// handlers registered on it; a node that's merely an internal prefix
+// of some longer route (e.g. "b" under "/a/b/c" when matching
+// "/a/b") is a dead end just like a missing child, and must fall
+// through the same way so a shorter param route at the same position
+// (e.g. "/a/:id") still gets a chance.
+//
+// method narrows this further: a node is only accepted as a match if
+// it has a handler registered for method. Without this, a literal
+// node that only handles POST would wrongly shadow a param route
+// registered for GET at the same position (e.g. "/a/b" POST and
+// "/a/:id" GET both registered: "GET /a/b" must fall through "b"'s
+// literal node, which has no GET handler, and match via :id). With
+// method == "", any node with at least one handler is accepted (see
+// hasHandler); the Allow header is built by collectMethods instead.
+func matchNode(node *routerNode, segments []string, params map[string]string, method string) *routerNode {
+	if len(segments) == 0 {
+		if !hasHandler(node, method) {
+			return nil
+		}
+		return node
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	if child, ok := node.children[seg]; ok {
+		if result := matchNode(child, rest, params, method); result != nil {
+			return result
+		}
+	}
+
+	if node.paramChild != nil {
+		prev, had := params[node.paramName]
+		params[node.paramName] = seg
+
+		if result := matchNode(node.paramChild, rest, params, method); result != nil {
+			return result
+		}
+
+		if had {
+			params[node.paramName] = prev
+		} else {
+			delete(params, node.paramName)
+		}
+	}
+
+	if node.catchAllChild != nil && hasHandler(node.catchAllChild, method) {
+		params[node.catchAllName] = strings.Join(segments, "/")
+		return node.catchAllChild
+	}
+
+	return nil
+}
+
+// hasHandler reports whether node is a usable match: for method ==
+// "" (path shape only), any handler at all; otherwise, a handler for
+// that specific method.
+func hasHandler(node *routerNode, method string) bool {
+	if method == "" {
+		return len(node.handlers) > 0
+	}
+	_, ok := node.handlers[method]
+	return ok
+}
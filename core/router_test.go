@@ -0,0 +1,133 @@
+package core
+
+import "testing"
+
+func dummyHandler(*Context) {}
+
+func TestRouterBacktracksPastDeadLiteralSubtree(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/a/b/c", dummyHandler)
+	rt.add("GET", "/a/:id/d", dummyHandler)
+
+	_, params, _, found := rt.match("GET", "/a/b/d")
+	if !found {
+		t.Fatalf("expected /a/b/d to match the :id branch, got no match")
+	}
+	if params["id"] != "b" {
+		t.Fatalf("expected param id=%q, got %q", "b", params["id"])
+	}
+}
+
+func TestRouterBacktracksPastHandlerlessLiteralPrefix(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/a/b/c", dummyHandler)
+	rt.add("GET", "/a/:id", dummyHandler)
+
+	// "b" has a literal node (it's a prefix of "/a/b/c"), but that
+	// node has no handlers of its own - it must not shadow the
+	// shorter param route registered at the same position.
+	_, params, _, found := rt.match("GET", "/a/b")
+	if !found {
+		t.Fatalf("expected /a/b to match the :id branch, got no match")
+	}
+	if params["id"] != "b" {
+		t.Fatalf("expected param id=%q, got %q", "b", params["id"])
+	}
+}
+
+func TestRouterBacktracksPastLiteralMethodMismatch(t *testing.T) {
+	rt := newRouter()
+	rt.add("POST", "/a/b", dummyHandler)
+	rt.add("GET", "/a/:id", dummyHandler)
+
+	// The literal "b" node has no GET handler, so it must not
+	// shadow the :id branch for a GET request even though it's a
+	// full path match for POST.
+	_, params, _, found := rt.match("GET", "/a/b")
+	if !found {
+		t.Fatalf("expected GET /a/b to match the :id branch, got no match")
+	}
+	if params["id"] != "b" {
+		t.Fatalf("expected param id=%q, got %q", "b", params["id"])
+	}
+}
+
+func TestRouterPrefersLiteralOverParam(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/a/b/c", dummyHandler)
+	rt.add("GET", "/a/:id/c", dummyHandler)
+
+	_, params, _, found := rt.match("GET", "/a/b/c")
+	if !found {
+		t.Fatalf("expected /a/b/c to match")
+	}
+	if _, ok := params["id"]; ok {
+		t.Fatalf("expected the literal branch to win with no :id captured, got %q", params["id"])
+	}
+}
+
+func TestRouterCatchAll(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/files/*path", dummyHandler)
+
+	_, params, _, found := rt.match("GET", "/files/a/b/c.txt")
+	if !found {
+		t.Fatalf("expected catch-all to match")
+	}
+	if params["path"] != "a/b/c.txt" {
+		t.Fatalf("expected path=%q, got %q", "a/b/c.txt", params["path"])
+	}
+}
+
+func TestRouterMethodMismatchReturns405Methods(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/home", dummyHandler)
+
+	_, _, methods, found := rt.match("POST", "/home")
+	if found {
+		t.Fatalf("expected no handler for POST /home")
+	}
+	if len(methods) != 1 || methods[0] != "GET" {
+		t.Fatalf("expected methods=[GET], got %v", methods)
+	}
+}
+
+func TestRouterMethodMismatch405CollectsMethodsAcrossBranches(t *testing.T) {
+	rt := newRouter()
+	rt.add("POST", "/users/export", dummyHandler)
+	rt.add("PUT", "/users/:id", dummyHandler)
+
+	// PUT matches via :id - confirms the two routes really do
+	// collide on shape, not just in theory.
+	if _, _, _, found := rt.match("PUT", "/users/export"); !found {
+		t.Fatalf("expected PUT /users/export to match the :id branch")
+	}
+
+	// Neither route serves DELETE, so this is a 405 - and it must
+	// report every method any branch at this path shape supports,
+	// not just whichever one (literal "export" vs :id) a shape-only
+	// walk happens to hit first.
+	_, _, methods, found := rt.match("DELETE", "/users/export")
+	if found {
+		t.Fatalf("expected no handler for DELETE /users/export")
+	}
+	want := map[string]bool{"POST": true, "PUT": true}
+	if len(methods) != len(want) {
+		t.Fatalf("expected methods=%v, got %v", want, methods)
+	}
+	for _, m := range methods {
+		if !want[m] {
+			t.Fatalf("unexpected method %q in %v", m, methods)
+		}
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	rt := newRouter()
+	rt.add("GET", "/home", dummyHandler)
+
+	_, _, methods, found := rt.match("GET", "/nope")
+	if found || methods != nil {
+		t.Fatalf("expected no match and no methods for an unregistered path")
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// bufferPool recycles the *bytes.Buffer ComponentHandler renders
+// into, so a busy route doesn't allocate a fresh buffer per
+// request.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// ComponentHandler renders a single component into an HTTP
+// response, modeled on templ's handler: unless StreamResponse is
+// set, Render runs into a pooled buffer first, so a render error
+// produces a clean 500 with an error message and no partial HTML,
+// instead of a 200 with half-written HTML (the failure mode
+// SSEHandler has, writing the error text inline into a stream that
+// already went out with a 200).
+type ComponentHandler struct {
+	// Component is rendered on every request this handler serves.
+	Component core.ComponentInterface
+
+	// Status is the response status written on success. Defaults
+	// to http.StatusOK.
+	Status int
+
+	// ContentType is the Content-Type header written on success.
+	// Defaults to "text/html; charset=utf-8".
+	ContentType string
+
+	// ErrorHandler, if set, takes over when Component.Render
+	// fails; it returns the core.HandlerFunc that actually writes
+	// the error response, so callers can render a custom error
+	// page, log with request-specific context, etc. If nil,
+	// ComponentHandler writes a plain-text 500.
+	ErrorHandler func(*core.Context, error) core.HandlerFunc
+
+	// StreamResponse, when true, writes headers and starts writing
+	// the rendered output immediately instead of buffering first.
+	// This is cheaper but brings back the partial-body-on-error
+	// risk buffering exists to avoid - only set it for components
+	// that can't fail to render.
+	StreamResponse bool
+
+	// ETag, when true, hashes the buffered output and sets it as
+	// the ETag header, returning 304 if it matches the request's
+	// If-None-Match. Only applies when StreamResponse is false,
+	// since streamed output can't be hashed before it's sent.
+	ETag bool
+}
+
+// status returns h.Status, defaulting to 200.
+func (h ComponentHandler) status() int {
+	if h.Status == 0 {
+		return http.StatusOK
+	}
+	return h.Status
+}
+
+// contentType returns h.ContentType, defaulting to HTML.
+func (h ComponentHandler) contentType() string {
+	if h.ContentType == "" {
+		return "text/html; charset=utf-8"
+	}
+	return h.ContentType
+}
+
+// Handler returns the core.HandlerFunc to register with
+// core.Sigma.Handle for this component.
+func (h ComponentHandler) Handler() core.HandlerFunc {
+	if h.StreamResponse {
+		return h.renderStream
+	}
+	return h.renderBuffered
+}
+
+// renderBuffered renders into a pooled buffer, then - only once
+// rendering has fully succeeded - writes headers and copies the
+// buffer to the response. A render error never reaches the client
+// as a partial body.
+func (h ComponentHandler) renderBuffered(ctx *core.Context) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	html, err := h.Component.Render()
+	if err != nil {
+		h.writeError(ctx, err)
+		return
+	}
+	buf.WriteString(html)
+
+	if h.ETag {
+		sum := sha256.Sum256(buf.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:]) + `"`
+		ctx.Resp.Header().Set("ETag", etag)
+		if ctx.Req.Header.Get("If-None-Match") == etag {
+			ctx.Resp.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	ctx.Resp.Header().Set("Content-Type", h.contentType())
+	ctx.Resp.WriteHeader(h.status())
+	io.Copy(ctx.Resp, buf)
+}
+
+// renderStream writes headers before rendering and streams the
+// result straight to the response. Faster, but a render error
+// after the status line is already written can't be turned into a
+// 500 - ErrorHandler (or the default) can still append an error
+// message to the body, just not change the status.
+func (h ComponentHandler) renderStream(ctx *core.Context) {
+	ctx.Resp.Header().Set("Content-Type", h.contentType())
+	ctx.Resp.WriteHeader(h.status())
+
+	html, err := h.Component.Render()
+	if err != nil {
+		if h.ErrorHandler != nil {
+			h.ErrorHandler(ctx, err)(ctx)
+			return
+		}
+		fmt.Fprintf(ctx.Resp, "Error: %v", err)
+		return
+	}
+
+	io.WriteString(ctx.Resp, html)
+}
+
+// writeError hands off to h.ErrorHandler if set, otherwise writes
+// a plain-text 500. Only used by renderBuffered, where the status
+// line hasn't been sent yet and a real error status is still
+// possible.
+func (h ComponentHandler) writeError(ctx *core.Context, err error) {
+	if h.ErrorHandler != nil {
+		h.ErrorHandler(ctx, err)(ctx)
+		return
+	}
+	http.Error(ctx.Resp, fmt.Sprintf("Failed to render component: %v", err), http.StatusInternalServerError)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// stubComponent is a minimal core.ComponentInterface for exercising
+// ComponentHandler without pulling in the components package.
+type stubComponent struct {
+	html string
+	err  error
+}
+
+func (s *stubComponent) Render() (string, error) { return s.html, s.err }
+func (s *stubComponent) Update(*core.Context)     {}
+func (s *stubComponent) State() map[string]interface{} {
+	return map[string]interface{}{"name": "stub"}
+}
+
+func TestComponentHandlerBufferedRenderError(t *testing.T) {
+	h := ComponentHandler{Component: &stubComponent{err: errors.New("boom")}}.Handler()
+
+	rec := httptest.NewRecorder()
+	h(&core.Context{Req: httptest.NewRequest(http.MethodGet, "/", nil), Resp: rec})
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 on a render error, got %d", rec.Code)
+	}
+	if rec.Body.Len() == 0 {
+		t.Fatalf("expected an error body")
+	}
+}
+
+func TestComponentHandlerBufferedRenderSuccess(t *testing.T) {
+	h := ComponentHandler{Component: &stubComponent{html: "<p>hi</p>"}}.Handler()
+
+	rec := httptest.NewRecorder()
+	h(&core.Context{Req: httptest.NewRequest(http.MethodGet, "/", nil), Resp: rec})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != "<p>hi</p>" {
+		t.Fatalf("expected the rendered body, got %q", rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Fatalf("expected the default content type, got %q", ct)
+	}
+}
+
+func TestComponentHandlerETagNotModified(t *testing.T) {
+	comp := &stubComponent{html: "<p>hi</p>"}
+	h := ComponentHandler{Component: comp, ETag: true}.Handler()
+
+	// First request: no If-None-Match, expect a 200 and an ETag.
+	rec := httptest.NewRecorder()
+	h(&core.Context{Req: httptest.NewRequest(http.MethodGet, "/", nil), Resp: rec})
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected an ETag header on the first response")
+	}
+
+	// Second request with the matching If-None-Match: expect 304.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec2 := httptest.NewRecorder()
+	h(&core.Context{Req: req, Resp: rec2})
+
+	if rec2.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 on a matching If-None-Match, got %d", rec2.Code)
+	}
+	if rec2.Body.Len() != 0 {
+		t.Fatalf("expected an empty body on 304, got %q", rec2.Body.String())
+	}
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the reported health of a component, modeled after the
+// liveness/readiness states used in Kubernetes-style health
+// checks.
+type State string
+
+const (
+	// Starting means the component hasn't reported a successful
+	// render yet. It's the initial state of a new Tracker.
+	Starting State = "starting"
+
+	// Healthy means the component is rendering/updating normally.
+	Healthy State = "healthy"
+
+	// Degraded means the component has hit enough consecutive
+	// failures to be worth an operator's attention, but hasn't
+	// been explicitly marked failed.
+	Degraded State = "degraded"
+
+	// Failed means the component (or an operator, via SetHealth)
+	// considers itself non-functional.
+	Failed State = "failed"
+)
+
+// Tracker records a component's health over time: its current
+// state/message, when it last changed, and how many consecutive
+// failures it's seen. It's safe for concurrent use.
+type Tracker struct {
+	mu sync.Mutex
+
+	state      State
+	message    string
+	lastUpdate time.Time
+
+	// consecutiveFailures counts RecordFailure calls since the last
+	// RecordSuccess. Once it reaches threshold, the state
+	// auto-transitions to Degraded.
+	consecutiveFailures int
+	threshold           int
+
+	// manual is set once Set has been called, and stays set from
+	// then on. While true, RecordSuccess/RecordFailure keep
+	// tracking consecutiveFailures and lastUpdate, but stop driving
+	// state/message automatically - otherwise the very next
+	// successful render would silently wipe out an operator's
+	// explicit Set(Failed, ...) within one render cycle. There's no
+	// way back to automatic tracking short of a new Tracker; Set
+	// is meant for state an operator, not a render/update outcome,
+	// is responsible for.
+	manual bool
+}
+
+// NewTracker creates a Tracker in the Starting state. threshold is
+// how many consecutive RecordFailure calls are needed before the
+// state auto-transitions to Degraded; a threshold <= 0 defaults to
+// 3.
+func NewTracker(threshold int) *Tracker {
+	if threshold <= 0 {
+		threshold = 3
+	}
+	return &Tracker{
+		state:     Starting,
+		threshold: threshold,
+	}
+}
+
+// Touch updates lastUpdate to now, without changing state. Callers
+// use this for any activity (e.g. a state change) that should
+// count as "last seen alive", whether or not it succeeded.
+func (t *Tracker) Touch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastUpdate = time.Now()
+}
+
+// RecordSuccess resets the failure count and, unless the current
+// state came from an explicit Set call, brings state back to
+// Healthy. A manually-set state is left alone: an unrelated render
+// succeeding doesn't mean whatever the operator flagged is fixed.
+func (t *Tracker) RecordSuccess() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consecutiveFailures = 0
+	t.lastUpdate = time.Now()
+
+	if t.manual {
+		return
+	}
+	t.state = Healthy
+	t.message = ""
+}
+
+// RecordFailure increments the consecutive failure count and, once
+// it reaches the tracker's threshold, transitions the state to
+// Degraded so operators can alert on it - unless the current state
+// came from an explicit Set call, which RecordFailure leaves alone
+// for the same reason RecordSuccess does.
+func (t *Tracker) RecordFailure(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.consecutiveFailures++
+	t.lastUpdate = time.Now()
+
+	if t.manual {
+		return
+	}
+	if t.consecutiveFailures >= t.threshold {
+		t.state = Degraded
+		t.message = err.Error()
+	}
+}
+
+// Set manually overrides state and message, e.g. for a component
+// that wants to report Failed for a reason a render/update error
+// alone wouldn't capture. From this call on, RecordSuccess and
+// RecordFailure no longer touch state/message - only another Set
+// call does - so a routine render succeeding elsewhere can't
+// silently clear what an operator just reported.
+func (t *Tracker) Set(state State, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.manual = true
+	t.state = state
+	t.message = message
+	t.lastUpdate = time.Now()
+}
+
+// Snapshot returns the tracker's current state, message, and last
+// update time.
+func (t *Tracker) Snapshot() (state, message string, lastUpdate time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.state), t.message, t.lastUpdate
+}
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrackerDegradesAfterThreshold(t *testing.T) {
+	tr := NewTracker(3)
+
+	tr.RecordFailure(errors.New("boom"))
+	tr.RecordFailure(errors.New("boom"))
+	if state, _, _ := tr.Snapshot(); state != string(Starting) {
+		t.Fatalf("expected state to stay %q below threshold, got %q", Starting, state)
+	}
+
+	tr.RecordFailure(errors.New("boom"))
+	if state, _, _ := tr.Snapshot(); state != string(Degraded) {
+		t.Fatalf("expected state %q at threshold, got %q", Degraded, state)
+	}
+}
+
+func TestTrackerRecordSuccessClearsAutomaticDegrade(t *testing.T) {
+	tr := NewTracker(1)
+	tr.RecordFailure(errors.New("boom"))
+
+	tr.RecordSuccess()
+	if state, msg, _ := tr.Snapshot(); state != string(Healthy) || msg != "" {
+		t.Fatalf("expected a clean Healthy state after success, got state=%q msg=%q", state, msg)
+	}
+}
+
+func TestTrackerManualOverrideSurvivesRecordSuccess(t *testing.T) {
+	tr := NewTracker(3)
+
+	tr.Set(Failed, "dependency down")
+	tr.RecordSuccess()
+
+	state, msg, _ := tr.Snapshot()
+	if state != string(Failed) || msg != "dependency down" {
+		t.Fatalf("expected manual override to survive a success, got state=%q msg=%q", state, msg)
+	}
+}
+
+func TestTrackerManualOverrideSurvivesRecordFailure(t *testing.T) {
+	tr := NewTracker(1)
+
+	tr.Set(Healthy, "forced healthy")
+	tr.RecordFailure(errors.New("boom"))
+
+	state, msg, _ := tr.Snapshot()
+	if state != string(Healthy) || msg != "forced healthy" {
+		t.Fatalf("expected manual override to survive a failure, got state=%q msg=%q", state, msg)
+	}
+}
+
+func TestTrackerSetAgainUpdatesOverride(t *testing.T) {
+	tr := NewTracker(1)
+
+	tr.Set(Failed, "dependency down")
+	tr.Set(Healthy, "dependency recovered")
+	tr.RecordFailure(errors.New("boom"))
+
+	state, msg, _ := tr.Snapshot()
+	if state != string(Healthy) || msg != "dependency recovered" {
+		t.Fatalf("expected the latest manual override to stick through a failure, got state=%q msg=%q", state, msg)
+	}
+}
@@ -7,12 +7,19 @@ import (
 	"github.com/akos011221/sigma/components"
 	"github.com/akos011221/sigma/core"
 	"github.com/akos011221/sigma/handlers"
+	"github.com/akos011221/sigma/middleware"
 	"github.com/akos011221/sigma/realtime"
 )
 
 func main() {
 	app := core.New()
 
+	// Global middleware runs for every route, in order: recover
+	// first so a panic anywhere downstream (including a long-lived
+	// SSE handler) can't take out the request goroutine, then log
+	// once we know the request didn't crash outright.
+	app.Use(middleware.Recovery(), middleware.Logger())
+
 	// Define a "status" component; our reusable UI piece.
 	// - Name: "status" (just an ID for Sigma to track it)
 	// - Template: HTML with a placeholder {{.Message}} for dynamic text.
@@ -44,8 +51,10 @@ func main() {
 		},
 	)
 
-	// Register the component with Sigma so it's available for routing and updates.
-	app.RegisterComponent(status)
+	// Register the component with Sigma so it's available for routing and
+	// updates. This also wires up a Broadcaster so SSE/WebSocket routes
+	// can push updates the moment the component's state changes.
+	statusUpdates := app.RegisterComponent(status)
 
 	// Define the root route (GET /)
 	app.Handle("GET", "/", func(c *core.Context) {
@@ -92,12 +101,35 @@ func main() {
 	})
 
 	// Route for SSE updates (GET /sse/status).
-	// realtime.SSEHandler streams the component’s rendered HTML every second.
-	app.Handle("GET", "/sse/status", realtime.SSEHandler(status))
+	// realtime.SSEHandler streams the component’s rendered HTML whenever
+	// statusUpdates is published to, instead of on a fixed interval.
+	app.Handle("GET", "/sse/status", realtime.SSEHandler(status, statusUpdates))
+
+	// Bidirectional route (GET /live/status): realtime.AutoHandler upgrades
+	// to a WebSocket when the client asks for one and falls back to SSE
+	// otherwise, so the same route serves both transports.
+	app.Handle("GET", "/live/status", realtime.AutoHandler(status, statusUpdates))
 
 	// Route for status updates (POST /update/status).
 	// handlers.UpdateComponent wraps our component’s Update logic in a handler.
 	app.Handle("POST", "/update/status", handlers.UpdateComponent(status))
 
+	// Route for fetching just the component's markup (GET /component/status),
+	// e.g. for a client that wants to re-fetch it directly instead of only
+	// listening over SSE. ComponentHandler buffers the render first, so a
+	// failure becomes a clean 500 instead of partial HTML, and ETag lets
+	// clients skip the body entirely when nothing changed.
+	app.Handle("GET", "/component/status", handlers.ComponentHandler{
+		Component: status,
+		ETag:      true,
+	}.Handler())
+
+	// Liveness/readiness/status routes for whatever's probing this
+	// process (a container orchestrator, a load balancer, an
+	// operator's browser tab).
+	app.Handle("GET", "/healthz", app.Liveness())
+	app.Handle("GET", "/readyz", app.Readiness())
+	app.Handle("GET", "/status", app.Status())
+
 	http.ListenAndServe(":8080", app)
 }
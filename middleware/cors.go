@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// CORSConfig controls which origins, methods, and headers a
+// CORS-protected route accepts. The zero value isn't usable -
+// construct one explicitly or use DefaultCORSConfig.
+type CORSConfig struct {
+	// AllowOrigin is the value sent back as
+	// Access-Control-Allow-Origin, e.g. "*" or a specific origin.
+	AllowOrigin string
+
+	// AllowMethods lists the methods sent back as
+	// Access-Control-Allow-Methods for preflight requests.
+	AllowMethods []string
+
+	// AllowHeaders lists the headers sent back as
+	// Access-Control-Allow-Headers for preflight requests.
+	AllowHeaders []string
+}
+
+// DefaultCORSConfig allows any origin and the common verbs/headers
+// this framework's components use (JSON bodies, form posts).
+var DefaultCORSConfig = CORSConfig{
+	AllowOrigin:  "*",
+	AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch, http.MethodOptions},
+	AllowHeaders: []string{"Content-Type"},
+}
+
+// CORS returns middleware that sets CORS headers per cfg and
+// short-circuits preflight OPTIONS requests with a 204.
+func CORS(cfg CORSConfig) core.Middleware {
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+
+	return func(ctx *core.Context) {
+		ctx.Resp.Header().Set("Access-Control-Allow-Origin", cfg.AllowOrigin)
+		ctx.Resp.Header().Set("Access-Control-Allow-Methods", methods)
+		ctx.Resp.Header().Set("Access-Control-Allow-Headers", headers)
+
+		if ctx.Req.Method == http.MethodOptions {
+			ctx.Resp.WriteHeader(http.StatusNoContent)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// gzipResponseWriter wraps http.ResponseWriter so Write goes
+// through a gzip.Writer instead of straight to the connection.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+// Flush lets a gzip-wrapped writer still satisfy http.Flusher, so
+// this middleware doesn't break streaming handlers (e.g. SSE) that
+// type-assert for it - it just flushes the gzip buffer first.
+func (w *gzipResponseWriter) Flush() {
+	w.gz.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Gzip returns middleware that compresses the response body when
+// the client sends "Accept-Encoding: gzip". Handlers downstream
+// don't need to know about it; they just write to ctx.Resp as
+// normal.
+func Gzip() core.Middleware {
+	return func(ctx *core.Context) {
+		if !strings.Contains(ctx.Req.Header.Get("Accept-Encoding"), "gzip") {
+			ctx.Next()
+			return
+		}
+
+		ctx.Resp.Header().Set("Content-Encoding", "gzip")
+		ctx.Resp.Header().Add("Vary", "Accept-Encoding")
+
+		gz := gzip.NewWriter(ctx.Resp)
+		defer gz.Close()
+
+		original := ctx.Resp
+		ctx.Resp = &gzipResponseWriter{ResponseWriter: original, gz: gz}
+		defer func() { ctx.Resp = original }()
+
+		ctx.Next()
+	}
+}
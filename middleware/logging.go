@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// Logger returns middleware that logs each request's method, path,
+// and how long the rest of the chain took to run.
+func Logger() core.Middleware {
+	return func(ctx *core.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		log.Printf("%s %s (%s)", ctx.Req.Method, ctx.Req.URL.Path, time.Since(start))
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// Recovery returns middleware that recovers a panic anywhere later
+// in the chain and turns it into a 500 instead of taking down the
+// request's goroutine - important for long-lived handlers like
+// realtime.SSEHandler, where an unrecovered panic would otherwise
+// silently kill that client's stream.
+func Recovery() core.Middleware {
+	return func(ctx *core.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic recovered: %v", r)
+				http.Error(ctx.Resp, "Internal server error", http.StatusInternalServerError)
+			}
+		}()
+
+		ctx.Next()
+	}
+}
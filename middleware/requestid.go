@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/akos011221/sigma/core"
+)
+
+// RequestIDKey is the key RequestID stores the generated ID under
+// via ctx.Set, so downstream middleware/handlers can fetch it with
+// ctx.Get(middleware.RequestIDKey).
+const RequestIDKey = "requestID"
+
+// RequestID returns middleware that takes the request's
+// X-Request-ID header if set, or otherwise generates a random ID,
+// stores it on the Context, and echoes it back as the X-Request-ID
+// response header so it can be correlated with logs.
+func RequestID() core.Middleware {
+	return func(ctx *core.Context) {
+		id := ctx.Req.Header.Get("X-Request-ID")
+		if id == "" {
+			id = generateRequestID()
+		}
+
+		ctx.Set(RequestIDKey, id)
+		ctx.Resp.Header().Set("X-Request-ID", id)
+
+		ctx.Next()
+	}
+}
+
+// generateRequestID returns a random 16-byte ID, hex-encoded. It
+// falls back to all-zeros if the system RNG is unavailable, which
+// in practice never happens on a real OS.
+func generateRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString(make([]byte, 16))
+	}
+	return hex.EncodeToString(b)
+}
@@ -8,20 +8,27 @@ import (
 	"github.com/akos011221/sigma/core"
 )
 
-// SSEHandler creates a handler that streams component
-// updates via SSE. It's a factory function returning
-// a core.HandlerFunc. It sets up a persistent connection
-// to push data to the client.
-func SSEHandler(component core.ComponentInterface) core.HandlerFunc {
+// keepAliveInterval is how often SSEHandler writes a comment line
+// so idle proxies/load balancers don't time out and close the
+// connection while nothing has changed.
+const keepAliveInterval = 25 * time.Second
+
+// SSEHandler creates a handler that streams component updates via
+// SSE. It's a factory function returning a core.HandlerFunc. Rather
+// than polling on a ticker, it subscribes to the component's
+// Broadcaster and only re-renders when something actually changed,
+// so propagation is immediate and an idle client costs nothing
+// beyond the keep-alive.
+func SSEHandler(component core.ComponentInterface, b *core.Broadcaster) core.HandlerFunc {
 	return func(c *core.Context) {
 		// Set HTTP headers for Server-Sent Events.
-		// "text/event-strem" tells the browser to
+		// "text/event-stream" tells the browser to
 		// expect SSE data.
 		c.Resp.Header().Set("Content-Type", "text/event-stream")
 		// "no-cache" prevents the browser from caching
 		// the stream.
 		c.Resp.Header().Set("Cache-Control", "no-cache")
-		// "keep-alive" ensure the connection stays open.
+		// "keep-alive" ensures the connection stays open.
 		c.Resp.Header().Set("Connection", "keep-alive")
 
 		// Check if the ResponseWriter supports flushing
@@ -35,47 +42,77 @@ func SSEHandler(component core.ComponentInterface) core.HandlerFunc {
 			return
 		}
 
-		// Create a ticker to simulate updates every second.
-		ticker := time.NewTicker(1 * time.Second)
-		// Defer stops the ticker when the function exists
-		// (e.g., client disconnects).
-		defer ticker.Stop()
+		// Subscribe to the component's broadcaster so this handler
+		// wakes up when SetState/Update publish a change, instead
+		// of on a fixed tick. Unsubscribe on return so the
+		// broadcaster doesn't keep a channel alive for a client
+		// that's gone.
+		changes := b.Subscribe()
+		defer b.Unsubscribe(changes)
+
+		// eventID is a monotonically increasing counter for this
+		// stream, starting at 1 on every connection. A reconnecting
+		// browser's Last-Event-ID isn't read; it just gets a fresh
+		// render of the current state.
+		var eventID int
+
+		// render writes one SSE event with the component's current
+		// HTML, including the id/event/retry fields browsers use
+		// to resume and reconnect correctly.
+		render := func() {
+			html, err := component.Render()
+			if err != nil {
+				// If rendering fails, send an error message in SSE
+				// format rather than silently dropping it.
+				fmt.Fprintf(c.Resp, "data: Error: %v\n\n", err)
+				flusher.Flush()
+				return
+			}
+
+			eventID++
+			fmt.Fprintf(c.Resp, "id: %d\n", eventID)
+			// "message" is the default event type, so a plain
+			// EventSource.onmessage listener (as on the demo page in
+			// main.go) receives it.
+			fmt.Fprintf(c.Resp, "event: message\n")
+			fmt.Fprintf(c.Resp, "retry: 2000\n")
+			fmt.Fprintf(c.Resp, "data: %s\n\n", html)
+			flusher.Flush()
+		}
+
+		// Send the current state immediately so the client doesn't
+		// wait for the next change to see anything.
+		render()
 
-		// Infinite loop to stream updates. This keeps
-		// the HTTP connection open until the client
-		// closes it.
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		// Loop until the client disconnects, re-rendering whenever
+		// a change is published and sending a keep-alive comment
+		// when the connection has been idle for a while.
 		for {
-			// Use select to handle multiple channels concurrently.
-			// This is Go's way of multiplexing I/O operations.
 			select {
 			case <-c.Req.Context().Done():
-				// This channel closes when the client disconnects
+				// This channel closes when the client disconnects.
 				// Context.Done() returns a channel that's closed
 				// when the request's context is canceled.
 				return
 
-			case <-ticker.C:
-				// This channel receives a time value very second
-				// from the ticker. Render the component's current
-				// state.
-				html, err := component.Render()
-				if err != nil {
-					// If rendering fails, send an error message
-					// in SSE format.
-					fmt.Fprintf(c.Resp, "data: Error: %v\n\n", err)
-				} else {
-					// Send the HTML as an SSE event.
-					// fmt.Fprintf writes to the ResponseWriter's internal
-					// buffer. SSE format requires "data: " followed by the
-					// payload and two newlines.
-					fmt.Fprintf(c.Resp, "data: %s\n\n", html)
+			case _, ok := <-changes:
+				if !ok {
+					// The broadcaster closed our channel (e.g. it
+					// was torn down); nothing left to subscribe to.
+					return
 				}
-				// Flush sends the data immediately to the client.
-				// This calls an internal method to write the
-				// buffer to the network.
+				render()
+
+			case <-keepAlive.C:
+				// ": ping" is an SSE comment - the browser ignores
+				// it, but it keeps the TCP connection looking
+				// active to any proxy in between.
+				fmt.Fprintf(c.Resp, ": ping\n\n")
 				flusher.Flush()
 			}
 		}
 	}
-
 }
@@ -0,0 +1,180 @@
+package realtime
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/akos011221/sigma/core"
+	"github.com/gorilla/websocket"
+)
+
+// discardResponseWriter satisfies http.ResponseWriter for the
+// synthesized Context passed to component.Update over WebSocket.
+// After upgrader.Upgrade hijacks the original connection, writing
+// to the original ResponseWriter is undefined behavior - so an
+// onUpdate that follows this codebase's own POST convention
+// (UpdateComponent writes "OK" to ctx.Resp) would otherwise corrupt
+// the hijacked socket. Responses to a WebSocket-sourced event go
+// back over the socket itself, via the broadcaster/render path in
+// WebSocketHandler, not through ctx.Resp - so writes here are
+// silently discarded rather than touching the real connection.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+
+func (w *discardResponseWriter) WriteHeader(statusCode int) {}
+
+// upgrader negotiates the WebSocket handshake. CheckOrigin is left
+// permissive to match this framework's "batteries included, lock
+// it down yourself" posture - wrap WebSocketHandler with
+// middleware.CORS-equivalent origin checking if you need it.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// eventFrame is the JSON shape a client sends over the socket to
+// trigger a component update: {"type": "...", "payload": {...}}.
+// type is decoded but not interpreted or passed on: only payload
+// reaches component.Update, through the synthesized Context.
+type eventFrame struct {
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// WebSocketHandler upgrades the connection and bridges it to
+// component, bidirectionally: every frame the client sends is
+// dispatched to component.Update, and any change published to b -
+// whether triggered by this client, another one, or server-side
+// code calling SetState directly - is re-rendered and pushed back
+// down this same socket. That collapses SSE's one-way push plus a
+// separate POST-per-event into a single connection.
+func WebSocketHandler(component core.ComponentInterface, b *core.Broadcaster) core.HandlerFunc {
+	return func(c *core.Context) {
+		conn, err := upgrader.Upgrade(c.Resp, c.Req, nil)
+		if err != nil {
+			// Upgrade already wrote an error response on failure.
+			return
+		}
+		defer conn.Close()
+
+		changes := b.Subscribe()
+		defer b.Unsubscribe(changes)
+
+		// done is closed once the read loop exits, either because
+		// the client closed the connection or a frame failed to
+		// decode, so the write loop below knows to stop too.
+		done := make(chan struct{})
+
+		go readLoop(conn, component, c, done)
+
+		render := func() bool {
+			html, err := component.Render()
+			if err != nil {
+				return conn.WriteJSON(map[string]string{"error": err.Error()}) == nil
+			}
+			return conn.WriteJSON(map[string]string{"html": html}) == nil
+		}
+
+		// Send the current state immediately so the client doesn't
+		// wait for the first change to see anything.
+		if !render() {
+			return
+		}
+
+		for {
+			select {
+			case <-c.Req.Context().Done():
+				return
+
+			case <-done:
+				return
+
+			case _, ok := <-changes:
+				if !ok {
+					return
+				}
+				if !render() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readLoop decodes one eventFrame per message and dispatches it to
+// component.Update, via a Context whose Req carries the frame's
+// payload as pre-parsed form values - so an onUpdate callback
+// written against ctx.Req.FormValue (the POST/UpdateComponent
+// convention) works unchanged whether the event arrived over
+// WebSocket or a regular form POST. It closes done on return.
+//
+// ctx.Resp is a discardResponseWriter, not the original request's
+// ResponseWriter - the connection behind that was hijacked by
+// upgrader.Upgrade, so writing to it here would corrupt the socket.
+// Any response to the event goes back over the socket itself, via
+// WebSocketHandler's render/broadcaster loop.
+func readLoop(conn *websocket.Conn, component core.ComponentInterface, c *core.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		var frame eventFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+
+		form := payloadToForm(frame.Payload)
+
+		// http.Request.ParseForm is a no-op once Form/PostForm are
+		// already set, so pre-populating them here means any
+		// existing onUpdate that calls ctx.Req.ParseForm() then
+		// ctx.Req.FormValue(...) sees these values untouched.
+		req := c.Req.Clone(c.Req.Context())
+		req.Method = http.MethodPost
+		req.Form = form
+		req.PostForm = form
+
+		component.Update(&core.Context{Req: req, Resp: &discardResponseWriter{}})
+	}
+}
+
+// payloadToForm flattens a decoded eventFrame payload into
+// url.Values, stringifying each value with fmt.Sprintf("%v", ...)
+// so ctx.Req.FormValue sees the same plain strings it would from an
+// HTML form POST.
+func payloadToForm(payload map[string]interface{}) url.Values {
+	form := make(url.Values, len(payload))
+	for k, v := range payload {
+		form.Set(k, fmt.Sprintf("%v", v))
+	}
+	return form
+}
+
+// AutoHandler picks WebSocketHandler when the client's request
+// asks to upgrade to a WebSocket, and falls back to SSEHandler
+// otherwise - so a single route (e.g. "/live/:component", using
+// core.Sigma's route params) can serve both transports for the
+// same component without the caller needing to know which one a
+// given client supports.
+func AutoHandler(component core.ComponentInterface, b *core.Broadcaster) core.HandlerFunc {
+	ws := WebSocketHandler(component, b)
+	sse := SSEHandler(component, b)
+
+	return func(c *core.Context) {
+		if strings.EqualFold(c.Req.Header.Get("Upgrade"), "websocket") {
+			ws(c)
+			return
+		}
+		sse(c)
+	}
+}
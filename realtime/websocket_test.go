@@ -0,0 +1,199 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akos011221/sigma/components"
+	"github.com/akos011221/sigma/core"
+	"github.com/gorilla/websocket"
+)
+
+// serveComponent wraps handler in an httptest.Server, adapting
+// net/http's (w, r) to the synthesized *core.Context WebSocketHandler
+// and SSEHandler expect.
+func serveComponent(handler core.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(&core.Context{Req: r, Resp: w})
+	}))
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to dial the WebSocket server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestWebSocketHandlerDispatchesEventFrameToUpdate(t *testing.T) {
+	updated := make(chan string, 1)
+	comp := components.NewComponent("counter", "{{.count}}", map[string]interface{}{"count": "0"},
+		func(c *components.Component, ctx *core.Context) {
+			count := ctx.Req.FormValue("count")
+			c.SetState("count", count)
+			updated <- count
+		})
+
+	b := core.NewBroadcaster()
+	comp.SetBroadcaster(b)
+
+	server := serveComponent(WebSocketHandler(comp, b))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+
+	// The handler renders once immediately on connect, before any
+	// frame is sent.
+	var initial map[string]string
+	if err := conn.ReadJSON(&initial); err != nil {
+		t.Fatalf("unexpected error reading the initial render: %v", err)
+	}
+	if initial["html"] != "0" {
+		t.Fatalf("expected the initial render to be %q, got %q", "0", initial["html"])
+	}
+
+	if err := conn.WriteJSON(eventFrame{Type: "increment", Payload: map[string]interface{}{"count": "5"}}); err != nil {
+		t.Fatalf("unexpected error writing the event frame: %v", err)
+	}
+
+	select {
+	case got := <-updated:
+		if got != "5" {
+			t.Fatalf("expected Update to see count=%q, got %q", "5", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for the event frame to reach component.Update")
+	}
+
+	// SetState inside onUpdate published a change, which the
+	// handler's render loop should push back down the same socket.
+	var rerender map[string]string
+	if err := conn.ReadJSON(&rerender); err != nil {
+		t.Fatalf("unexpected error reading the pushed re-render: %v", err)
+	}
+	if rerender["html"] != "5" {
+		t.Fatalf("expected the pushed re-render to be %q, got %q", "5", rerender["html"])
+	}
+}
+
+func TestWebSocketHandlerPushesBroadcasterChangesAsRerender(t *testing.T) {
+	comp := components.NewComponent("counter", "{{.count}}", map[string]interface{}{"count": "0"}, nil)
+
+	b := core.NewBroadcaster()
+	comp.SetBroadcaster(b)
+
+	server := serveComponent(WebSocketHandler(comp, b))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+
+	var initial map[string]string
+	if err := conn.ReadJSON(&initial); err != nil {
+		t.Fatalf("unexpected error reading the initial render: %v", err)
+	}
+
+	// Simulate a server-side state change with nothing to do with
+	// this particular socket - the broadcaster should still push a
+	// fresh render down it.
+	comp.SetState("count", "9")
+
+	var rerender map[string]string
+	if err := conn.ReadJSON(&rerender); err != nil {
+		t.Fatalf("unexpected error reading the pushed re-render: %v", err)
+	}
+	if rerender["html"] != "9" {
+		t.Fatalf("expected the pushed re-render to be %q, got %q", "9", rerender["html"])
+	}
+}
+
+func TestAutoHandlerUpgradesOnWebSocketHeader(t *testing.T) {
+	comp := components.NewComponent("counter", "{{.count}}", map[string]interface{}{"count": "0"}, nil)
+	b := core.NewBroadcaster()
+
+	server := serveComponent(AutoHandler(comp, b))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+
+	var initial map[string]string
+	if err := conn.ReadJSON(&initial); err != nil {
+		t.Fatalf("expected AutoHandler to upgrade and push a render frame, got error: %v", err)
+	}
+	if initial["html"] != "0" {
+		t.Fatalf("expected the initial render to be %q, got %q", "0", initial["html"])
+	}
+}
+
+func TestAutoHandlerFallsBackToSSEWithoutUpgradeHeader(t *testing.T) {
+	comp := components.NewComponent("counter", "{{.count}}", map[string]interface{}{"count": "0"}, nil)
+	b := core.NewBroadcaster()
+
+	server := serveComponent(AutoHandler(comp, b))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building the request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error making the request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected a plain GET to fall back to SSE, got Content-Type %q", ct)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("unexpected error reading the SSE stream: %v", err)
+	}
+	if !strings.HasPrefix(line, "id: ") {
+		t.Fatalf("expected an SSE id field, got %q", line)
+	}
+
+	// Let the SSE handler's goroutine exit by cancelling the
+	// request context, same as a client disconnecting.
+	cancel()
+}
+
+func TestPayloadToForm(t *testing.T) {
+	form := payloadToForm(map[string]interface{}{"message": "hello", "count": 3.0})
+
+	if got := form.Get("message"); got != "hello" {
+		t.Fatalf("expected message=%q, got %q", "hello", got)
+	}
+	if got := form.Get("count"); got != "3" {
+		t.Fatalf("expected count=%q, got %q", "3", got)
+	}
+}
+
+func TestDiscardResponseWriterDoesNotPanic(t *testing.T) {
+	var w discardResponseWriter
+
+	w.WriteHeader(200)
+	n, err := w.Write([]byte("should be dropped, not sent over the hijacked socket"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != len("should be dropped, not sent over the hijacked socket") {
+		t.Fatalf("expected Write to report the full length, got %d", n)
+	}
+	if w.Header() == nil {
+		t.Fatalf("expected Header() to return a usable map")
+	}
+}